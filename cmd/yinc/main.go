@@ -0,0 +1,74 @@
+// Command yinc expands !include/!replace directives in a YAML document.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/alecthomas/kong"
+	"github.com/togashi/yinc-go/yinc"
+)
+
+const VERSION = "yinc version 0.3.0"
+
+var CLI struct {
+	IndentWidth          int              `help:"Indent width." short:"w" default:"2"`
+	OutputMultiDocuments bool             `help:"Output multiple documents." short:"m"`
+	IncludeTag           string           `help:"Specify include tag." default:"!include"`
+	ReplaceTag           string           `help:"Specify replace tag." default:"!replace"`
+	IncludeIfTag         string           `help:"Specify conditional include tag." default:"!include-if"`
+	Tag                  []string         `help:"Tag available to !include-if expressions, as name or name=false." name:"tag"`
+	MaxIncludeDepth      int              `help:"Fail once nested includes exceed this many levels (0 for no limit)." default:"0"`
+	Version              kong.VersionFlag `help:"Show version." short:"V"`
+	Files                []string         `help:"Files to process." arg:"" type:"path" optional:""`
+}
+
+// parseTags turns "--tag" flags of the form "name" or "name=false" into
+// the map !include-if expressions are evaluated against.
+func parseTags(flags []string) map[string]bool {
+	tags := make(map[string]bool, len(flags))
+	for _, f := range flags {
+		name, value, ok := strings.Cut(f, "=")
+		if !ok || value != "false" {
+			tags[name] = true
+			continue
+		}
+		tags[name] = false
+	}
+	return tags
+}
+
+func main() {
+	kong.Parse(&CLI, kong.Vars{"version": VERSION})
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	if len(CLI.Files) == 0 {
+		CLI.Files = append(CLI.Files, "-")
+	}
+	proc := yinc.NewProcessor(yinc.Options{
+		IndentWidth:          CLI.IndentWidth,
+		IncludeTag:           CLI.IncludeTag,
+		ReplaceTag:           CLI.ReplaceTag,
+		IncludeIfTag:         CLI.IncludeIfTag,
+		Tags:                 parseTags(CLI.Tag),
+		MaxIncludeDepth:      CLI.MaxIncludeDepth,
+		OutputMultiDocuments: CLI.OutputMultiDocuments,
+	})
+	ctx := context.Background()
+	for i, file := range CLI.Files {
+		if CLI.OutputMultiDocuments && i > 0 {
+			os.Stdout.WriteString("---\n")
+		}
+		if err := proc.Process(ctx, file, os.Stdout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package yinc
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// splitCondition splits the text following an !include-if tag into its
+// parenthesized boolean expression and the include spec after it, e.g.
+// "(prod && !debug) configs/prod/*.yaml" -> "prod && !debug", "configs/prod/*.yaml".
+func splitCondition(rest string) (expr string, spec string, err error) {
+	rest = strings.TrimLeft(rest, " ")
+	if !strings.HasPrefix(rest, "(") {
+		return "", "", fmt.Errorf("!include-if requires a parenthesized expression, got %q", rest)
+	}
+	depth := 0
+	for i, r := range rest {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return rest[1:i], strings.TrimSpace(rest[i+1:]), nil
+			}
+		}
+	}
+	return "", "", fmt.Errorf("unbalanced parens in %q", rest)
+}
+
+// baseTags returns the automatic tags ("os:<GOOS>", "arch:<GOARCH>")
+// merged with the user-supplied Options.Tags, which take precedence.
+func baseTags(tags map[string]bool) map[string]bool {
+	merged := map[string]bool{
+		"os:" + runtime.GOOS:     true,
+		"arch:" + runtime.GOARCH: true,
+	}
+	for k, v := range tags {
+		merged[k] = v
+	}
+	return merged
+}
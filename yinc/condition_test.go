@@ -0,0 +1,58 @@
+package yinc
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestSplitCondition(t *testing.T) {
+	cases := []struct {
+		rest     string
+		wantExpr string
+		wantSpec string
+	}{
+		{"(prod && !debug) configs/prod/*.yaml", "prod && !debug", "configs/prod/*.yaml"},
+		{"(linux) a.yaml", "linux", "a.yaml"},
+		{"  (prod) a.yaml", "prod", "a.yaml"},
+		{"((nested) || x) a.yaml", "(nested) || x", "a.yaml"},
+	}
+	for _, c := range cases {
+		expr, spec, err := splitCondition(c.rest)
+		if err != nil {
+			t.Fatalf("splitCondition(%q): %v", c.rest, err)
+		}
+		if expr != c.wantExpr || spec != c.wantSpec {
+			t.Errorf("splitCondition(%q) = (%q, %q), want (%q, %q)", c.rest, expr, spec, c.wantExpr, c.wantSpec)
+		}
+	}
+}
+
+func TestSplitConditionErrors(t *testing.T) {
+	cases := []string{
+		"a.yaml",
+		"(prod a.yaml",
+	}
+	for _, rest := range cases {
+		if _, _, err := splitCondition(rest); err == nil {
+			t.Errorf("splitCondition(%q): expected error, got nil", rest)
+		}
+	}
+}
+
+func TestBaseTags(t *testing.T) {
+	tags := baseTags(map[string]bool{"prod": true})
+	if !tags["prod"] {
+		t.Error(`expected "prod" to be true`)
+	}
+	if _, ok := tags["os:"+runtime.GOOS]; !ok {
+		t.Error("expected automatic os: tag to be set")
+	}
+}
+
+func TestBaseTagsOverridesAutomatic(t *testing.T) {
+	osTag := "os:" + runtime.GOOS
+	tags := baseTags(map[string]bool{osTag: false})
+	if tags[osTag] {
+		t.Errorf("expected user override of %q to take precedence over automatic tag", osTag)
+	}
+}
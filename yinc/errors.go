@@ -0,0 +1,32 @@
+package yinc
+
+import "fmt"
+
+// ProcessError describes a failure that occurred while processing a
+// particular include spec. It carries enough context (the spec and, when
+// known, the line within it) to let callers report something actionable
+// instead of a bare panic trace.
+type ProcessError struct {
+	Spec string
+	Line int
+	Err  error
+}
+
+func (e *ProcessError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("yinc: %s:%d: %s", e.Spec, e.Line, e.Err)
+	}
+	return fmt.Sprintf("yinc: %s: %s", e.Spec, e.Err)
+}
+
+func (e *ProcessError) Unwrap() error {
+	return e.Err
+}
+
+// wrapErr returns err wrapped in a *ProcessError unless err is already nil.
+func wrapErr(spec string, line int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ProcessError{Spec: spec, Line: line, Err: err}
+}
@@ -0,0 +1,37 @@
+package yinc
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestFileSourceIDNormalizesOSPaths(t *testing.T) {
+	a := fileSourceID(osFileSystem{}, "a.yaml")
+	b := fileSourceID(osFileSystem{}, "./a.yaml")
+	if a != b {
+		t.Errorf("fileSourceID(%q) = %q, fileSourceID(%q) = %q, want equal", "a.yaml", a, "./a.yaml", b)
+	}
+}
+
+func TestFileSourceIDDistinguishesDifferentOSPaths(t *testing.T) {
+	a := fileSourceID(osFileSystem{}, "a.yaml")
+	b := fileSourceID(osFileSystem{}, "b.yaml")
+	if a == b {
+		t.Errorf("fileSourceID(a.yaml) == fileSourceID(b.yaml) = %q, want different", a)
+	}
+}
+
+func TestFileSourceIDScopesToMountedFS(t *testing.T) {
+	fsysA := fstest.MapFS{"a.yaml": &fstest.MapFile{}}
+	fsysB := fstest.MapFS{"a.yaml": &fstest.MapFile{}}
+	// Same relative spec, different mounted FileSystem instances, must not
+	// collide: two sandboxes that both happen to contain "a.yaml" are not
+	// the same source.
+	if fileSourceID(fsysA, "a.yaml") == fileSourceID(fsysB, "a.yaml") {
+		t.Error("fileSourceID collided across distinct mounted FileSystems")
+	}
+	// Same FileSystem, same spec, must be stable.
+	if fileSourceID(fsysA, "a.yaml") != fileSourceID(fsysA, "a.yaml") {
+		t.Error("fileSourceID not stable for the same mounted FileSystem and spec")
+	}
+}
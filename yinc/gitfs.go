@@ -0,0 +1,46 @@
+package yinc
+
+import (
+	"io/fs"
+
+	"github.com/go-git/go-billy/v5"
+)
+
+// billyFS adapts a billy.Filesystem (as returned by a go-git worktree) to
+// io/fs.FS, so repo contents can be read with doublestar.Glob the same
+// way local files are.
+type billyFS struct {
+	fs billy.Filesystem
+}
+
+func (b billyFS) Open(name string) (fs.File, error) {
+	f, err := b.fs.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return billyFile{File: f, fs: b.fs, name: name}, nil
+}
+
+// ReadDir implements fs.ReadDirFS, so doublestar.Glob can enumerate
+// directories to match wildcard patterns against a cloned repo's worktree.
+func (b billyFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	infos, err := b.fs.ReadDir(name)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]fs.DirEntry, len(infos))
+	for i, info := range infos {
+		entries[i] = fs.FileInfoToDirEntry(info)
+	}
+	return entries, nil
+}
+
+type billyFile struct {
+	billy.File
+	fs   billy.Filesystem
+	name string
+}
+
+func (f billyFile) Stat() (fs.FileInfo, error) {
+	return f.fs.Stat(f.name)
+}
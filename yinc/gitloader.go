@@ -0,0 +1,195 @@
+package yinc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// GitLoader loads specs of the form "git://<url>//<path>@<ref>", fetching
+// <url> at <ref> into an in-memory clone (billy memfs + memory storer, so
+// no working tree ever touches disk) and reading <path> out of it. Clones
+// are cached by (url, ref) for the lifetime of the GitLoader so repeated
+// includes from the same revision only fetch once.
+type GitLoader struct {
+	// Auth, if set, authenticates the clone (SSH key, token, basic auth).
+	Auth transport.AuthMethod
+
+	mu     sync.Mutex
+	clones map[gitRef]*git.Repository
+}
+
+type gitRef struct {
+	url string
+	ref string
+}
+
+func (l *GitLoader) Open(ctx context.Context, spec string) (io.ReadCloser, Resolver, SourceID, error) {
+	url, ref, path, err := parseGitSpec(spec)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	repo, err := l.clone(ctx, url, ref)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return nil, nil, "", err
+	}
+	f, err := wt.Filesystem.Open(path)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	resolver := &gitResolver{url: url, ref: ref, dir: dirOf(path), fs: billyFS{wt.Filesystem}}
+
+	// Key the SourceID on the resolved commit, not the ref string, so
+	// "@main" and the commit it happens to point at are recognised as the
+	// same source.
+	commit := ref
+	if head, err := repo.Head(); err == nil {
+		commit = head.Hash().String()
+	}
+	id := SourceID(fmt.Sprintf("git:%s@%s//%s", url, commit, path))
+	return f, resolver, id, nil
+}
+
+// clone returns the (possibly cached) in-memory clone of url at ref. url
+// still carries yinc's "git://" loader-prefix scheme, not a real transport
+// URL, so it is translated via transportURL before being handed to go-git.
+func (l *GitLoader) clone(ctx context.Context, url, ref string) (*git.Repository, error) {
+	key := gitRef{url: url, ref: ref}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.clones == nil {
+		l.clones = make(map[gitRef]*git.Repository)
+	}
+	if repo, ok := l.clones[key]; ok {
+		return repo, nil
+	}
+
+	remote := transportURL(url)
+	repo, err := git.CloneContext(ctx, memory.NewStorage(), memfs.New(), &git.CloneOptions{
+		URL:           remote,
+		Auth:          l.Auth,
+		ReferenceName: plumbing.NewBranchReferenceName(ref),
+		SingleBranch:  true,
+		Depth:         1,
+	})
+	if err != nil {
+		// ref may be a tag or a commit rather than a branch; fall back to
+		// a full clone and an explicit checkout of the resolved ref.
+		repo, err = git.CloneContext(ctx, memory.NewStorage(), memfs.New(), &git.CloneOptions{
+			URL:  remote,
+			Auth: l.Auth,
+		})
+		if err != nil {
+			return nil, err
+		}
+		hash, err := resolveRef(repo, ref)
+		if err != nil {
+			return nil, err
+		}
+		wt, err := repo.Worktree()
+		if err != nil {
+			return nil, err
+		}
+		if err := wt.Checkout(&git.CheckoutOptions{Hash: hash}); err != nil {
+			return nil, err
+		}
+	}
+
+	l.clones[key] = repo
+	return repo, nil
+}
+
+func resolveRef(repo *git.Repository, ref string) (plumbing.Hash, error) {
+	if h, err := repo.ResolveRevision(plumbing.Revision(ref)); err == nil {
+		return *h, nil
+	}
+	tagRef, err := repo.Tag(ref)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("unresolvable ref %q: %w", ref, err)
+	}
+	return tagRef.Hash(), nil
+}
+
+// parseGitSpec splits a "git://<url>//<path>@<ref>" spec into its parts.
+// <ref> defaults to "main" when omitted.
+func parseGitSpec(spec string) (url, ref, path string, err error) {
+	rest := strings.TrimPrefix(spec, "git://")
+	url, path, ok := strings.Cut(rest, "//")
+	if !ok {
+		return "", "", "", fmt.Errorf("missing //<path> in git spec %q", spec)
+	}
+	ref = "main"
+	if at := strings.LastIndex(path, "@"); at >= 0 {
+		ref = path[at+1:]
+		path = path[:at]
+	}
+	return "git://" + url, ref, path, nil
+}
+
+// transportURL strips yinc's "git://" loader-prefix scheme from url and
+// maps what's left onto a real go-git transport URL. go-git has no notion
+// of "git://" as a loader prefix: unprefixed, it treats git:// as the
+// git-daemon protocol, which GitHub and most hosts have disabled since
+// 2021. A bare "host/path" (no scheme, no ssh "user@host:" form) is
+// assumed to be an https:// remote; anything already carrying its own
+// scheme or an ssh login is passed through unchanged.
+func transportURL(url string) string {
+	url = strings.TrimPrefix(url, "git://")
+	if strings.Contains(url, "://") || strings.HasPrefix(url, "git@") {
+		return url
+	}
+	return "https://" + url
+}
+
+// gitResolver resolves nested specs relative to the directory of the file
+// that contained them, inside the same cloned repository and ref, and
+// globs via the repo's in-memory filesystem.
+type gitResolver struct {
+	url string
+	ref string
+	dir string
+	fs  billyFS
+}
+
+func (r *gitResolver) Resolve(spec string) string {
+	if strings.Contains(spec, "://") {
+		return spec
+	}
+	return fmt.Sprintf("%s//%s@%s", r.url, r.join(spec), r.ref)
+}
+
+// Glob expands pattern as a bare path inside the repo (not a full git://
+// spec) against the in-memory worktree filesystem, then wraps each match
+// back into a full spec for SubStream.
+func (r *gitResolver) Glob(pattern string) ([]string, error) {
+	matches, err := doublestar.Glob(r.fs, r.join(pattern))
+	if err != nil {
+		return nil, err
+	}
+	specs := make([]string, len(matches))
+	for i, m := range matches {
+		specs[i] = fmt.Sprintf("%s//%s@%s", r.url, m, r.ref)
+	}
+	return specs, nil
+}
+
+func (r *gitResolver) join(path string) string {
+	if r.dir == "" {
+		return path
+	}
+	return r.dir + "/" + path
+}
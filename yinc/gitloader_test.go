@@ -0,0 +1,43 @@
+package yinc
+
+import "testing"
+
+func TestParseGitSpec(t *testing.T) {
+	cases := []struct {
+		spec             string
+		wantURL, wantRef string
+		wantPath         string
+	}{
+		{"git://github.com/togashi/yinc-go//configs/base.yaml", "git://github.com/togashi/yinc-go", "main", "configs/base.yaml"},
+		{"git://github.com/togashi/yinc-go//configs/base.yaml@v1.2.3", "git://github.com/togashi/yinc-go", "v1.2.3", "configs/base.yaml"},
+	}
+	for _, c := range cases {
+		url, ref, path, err := parseGitSpec(c.spec)
+		if err != nil {
+			t.Fatalf("parseGitSpec(%q): %v", c.spec, err)
+		}
+		if url != c.wantURL || ref != c.wantRef || path != c.wantPath {
+			t.Errorf("parseGitSpec(%q) = (%q, %q, %q), want (%q, %q, %q)",
+				c.spec, url, ref, path, c.wantURL, c.wantRef, c.wantPath)
+		}
+	}
+}
+
+func TestParseGitSpecRequiresPath(t *testing.T) {
+	if _, _, _, err := parseGitSpec("git://github.com/togashi/yinc-go@main"); err == nil {
+		t.Error("parseGitSpec: expected error for spec missing //<path>, got nil")
+	}
+}
+
+func TestTransportURL(t *testing.T) {
+	cases := []struct{ url, want string }{
+		{"git://github.com/togashi/yinc-go", "https://github.com/togashi/yinc-go"},
+		{"git://https://github.com/togashi/yinc-go", "https://github.com/togashi/yinc-go"},
+		{"git://git@github.com:togashi/yinc-go.git", "git@github.com:togashi/yinc-go.git"},
+	}
+	for _, c := range cases {
+		if got := transportURL(c.url); got != c.want {
+			t.Errorf("transportURL(%q) = %q, want %q", c.url, got, c.want)
+		}
+	}
+}
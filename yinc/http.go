@@ -0,0 +1,76 @@
+package yinc
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// httpLoader fetches http(s):// specs.
+type httpLoader struct {
+	Client *http.Client
+}
+
+func (l *httpLoader) Open(ctx context.Context, spec string) (io.ReadCloser, Resolver, SourceID, error) {
+	u, err := url.Parse(spec)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, spec, nil)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	client := l.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return resp.Body, &urlResolver{base: u}, normalizeURL(u), nil
+}
+
+// normalizeURL canonicalizes u so two specs differing only in scheme/host
+// case or query parameter order resolve to the same SourceID.
+func normalizeURL(u *url.URL) SourceID {
+	norm := *u
+	norm.Scheme = strings.ToLower(norm.Scheme)
+	norm.Host = strings.ToLower(norm.Host)
+	norm.Fragment = ""
+	q := norm.Query()
+	sorted := make(url.Values, len(q))
+	for k, v := range q {
+		sv := append([]string(nil), v...)
+		sort.Strings(sv)
+		sorted[k] = sv
+	}
+	norm.RawQuery = sorted.Encode()
+	return SourceID("url:" + norm.String())
+}
+
+// urlResolver resolves nested specs as URL references against the base
+// URL they were found in, so a relative include in a fetched document
+// resolves to another URL on the same host rather than a local file.
+type urlResolver struct {
+	base *url.URL
+}
+
+func (r *urlResolver) Resolve(spec string) string {
+	ref, err := url.Parse(spec)
+	if err != nil {
+		return spec
+	}
+	return r.base.ResolveReference(ref).String()
+}
+
+// Glob satisfies globResolver. URLs have no wildcard-matching semantics, so
+// this just resolves pattern as a literal spec rather than letting
+// Stream.glob fall back to doublestar.FilepathGlob, which would look for a
+// matching path on the local filesystem and silently find nothing.
+func (r *urlResolver) Glob(pattern string) ([]string, error) {
+	return []string{r.Resolve(pattern)}, nil
+}
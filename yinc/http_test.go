@@ -0,0 +1,35 @@
+package yinc
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestNormalizeURL(t *testing.T) {
+	cases := []struct{ a, b string }{
+		{"HTTP://Example.com/a.yaml", "http://example.com/a.yaml"},
+		{"http://example.com/a.yaml?b=2&a=1", "http://example.com/a.yaml?a=1&b=2"},
+		{"http://example.com/a.yaml#section", "http://example.com/a.yaml"},
+	}
+	for _, c := range cases {
+		ua, err := url.Parse(c.a)
+		if err != nil {
+			t.Fatalf("url.Parse(%q): %v", c.a, err)
+		}
+		ub, err := url.Parse(c.b)
+		if err != nil {
+			t.Fatalf("url.Parse(%q): %v", c.b, err)
+		}
+		if got, want := normalizeURL(ua), normalizeURL(ub); got != want {
+			t.Errorf("normalizeURL(%q) = %q, normalizeURL(%q) = %q, want equal", c.a, got, c.b, want)
+		}
+	}
+}
+
+func TestNormalizeURLDistinguishesDifferentPaths(t *testing.T) {
+	a, _ := url.Parse("http://example.com/a.yaml")
+	b, _ := url.Parse("http://example.com/b.yaml")
+	if normalizeURL(a) == normalizeURL(b) {
+		t.Error("normalizeURL collided across different paths")
+	}
+}
@@ -0,0 +1,45 @@
+package yinc
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestStreamProcessIncludeIfTrueActsLikeInclude(t *testing.T) {
+	loader := &memLoader{
+		files: map[string]string{
+			"mem:root": "  key: !include-if (prod) mem:leaf\n",
+			"mem:leaf": "value: 1\n",
+		},
+	}
+	opts := Options{}.withDefaults()
+	tags := map[string]bool{"prod": true}
+	var out strings.Builder
+	stream := newStream(opts, newMemRegistry(loader), tags, "mem:root", &out)
+	if err := stream.Process(context.Background()); err != nil {
+		t.Fatalf("Process() error = %v, want nil", err)
+	}
+	if want := "  key:\n    value: 1\n"; out.String() != want {
+		t.Fatalf("output = %q, want %q", out.String(), want)
+	}
+}
+
+func TestStreamProcessIncludeIfFalseDropsLine(t *testing.T) {
+	loader := &memLoader{
+		files: map[string]string{
+			"mem:root": "before: 1\n  key: !include-if (prod) mem:leaf\nafter: 1\n",
+			"mem:leaf": "value: 1\n",
+		},
+	}
+	opts := Options{}.withDefaults()
+	tags := map[string]bool{"prod": false}
+	var out strings.Builder
+	stream := newStream(opts, newMemRegistry(loader), tags, "mem:root", &out)
+	if err := stream.Process(context.Background()); err != nil {
+		t.Fatalf("Process() error = %v, want nil", err)
+	}
+	if want := "before: 1\nafter: 1\n"; out.String() != want {
+		t.Fatalf("output = %q, want %q", out.String(), want)
+	}
+}
@@ -0,0 +1,9 @@
+package yinc
+
+import "github.com/ghodss/yaml"
+
+// jsonToYAML converts JSON document bytes to their YAML equivalent, used
+// by the "$(json ...)" pseudo-spec.
+func jsonToYAML(data []byte) ([]byte, error) {
+	return yaml.JSONToYAML(data)
+}
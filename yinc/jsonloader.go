@@ -0,0 +1,39 @@
+package yinc
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+)
+
+// jsonLoader reads a "$(json <path>)" spec, converting the referenced
+// JSON file to YAML before handing it to the processor.
+type jsonLoader struct {
+	FS FileSystem
+}
+
+func (l *jsonLoader) Open(ctx context.Context, spec string) (io.ReadCloser, Resolver, SourceID, error) {
+	jsonfile := strings.TrimPrefix(spec, "$(json ")
+	jsonfile = strings.TrimSuffix(jsonfile, ")")
+
+	fsys := l.FS
+	if fsys == nil {
+		fsys = osFileSystem{}
+	}
+	f, err := fsys.Open(jsonfile)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	defer f.Close()
+	jsonBytes, err := io.ReadAll(f)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	yamlBytes, err := jsonToYAML(jsonBytes)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	resolver := &fileResolver{fs: fsys, dir: dirOf(jsonfile)}
+	return io.NopCloser(bytes.NewReader(yamlBytes)), resolver, fileSourceID(fsys, jsonfile), nil
+}
@@ -0,0 +1,53 @@
+package yinc
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// LineElements matches a single line against the configured include/replace
+// tags and holds the captured submatches.
+type LineElements struct {
+	pattern  *regexp.Regexp
+	submatch struct {
+		indent string
+		text   string
+		tag    string
+		spec   string
+	}
+}
+
+// NewLine builds a LineElements matcher for the given include, replace and
+// conditional-include tags.
+func NewLine(includeTag string, replaceTag string, includeIfTag string) *LineElements {
+	tags := strings.Replace(fmt.Sprintf("(%s|%s|%s)", includeTag, replaceTag, includeIfTag), "!", "\\!", -1)
+	l := &LineElements{}
+	l.pattern = regexp.MustCompile(`^(?P<indent>\s*)((?P<text>[^\s#]+)\s+)?(?<tag>` + tags + `)\s+(?P<spec>.+)$`)
+	return l
+}
+
+// Match reports whether line carries an include/replace tag, and if so
+// populates the submatch fields.
+func (l *LineElements) Match(line []byte) bool {
+	match := l.pattern.FindSubmatch(line)
+	if match == nil {
+		return false
+	}
+	for i, name := range l.pattern.SubexpNames() {
+		if i != 0 && name != "" && i < len(match) {
+			value := string(match[i])
+			switch name {
+			case "indent":
+				l.submatch.indent = value
+			case "text":
+				l.submatch.text = value
+			case "tag":
+				l.submatch.tag = value
+			case "spec":
+				l.submatch.spec = value
+			}
+		}
+	}
+	return true
+}
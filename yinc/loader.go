@@ -0,0 +1,133 @@
+package yinc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Resolver resolves a spec found inside an already-opened source (e.g. a
+// nested !include) into the spec its own Loader should be given. It
+// replaces the old working-directory-chdir hack: each Loader decides for
+// itself what "relative to here" means, whether "here" is a directory on
+// disk, an HTTP URL, or an entry inside a Git tree.
+type Resolver interface {
+	Resolve(spec string) string
+}
+
+// globResolver is implemented by Resolvers that need to expand a glob
+// pattern themselves rather than have the processor resolve-then-glob it
+// generically; this is what lets a scheme like git:// glob a bare
+// in-repo path and turn each match back into a full spec.
+type globResolver interface {
+	Resolver
+	Glob(pattern string) ([]string, error)
+}
+
+// identityResolver resolves every spec to itself. It is used for sources
+// that have no notion of a containing directory, such as stdin or the
+// output of "$(shell ...)".
+type identityResolver struct{}
+
+func (identityResolver) Resolve(spec string) string { return spec }
+
+// Glob satisfies globResolver. A source with no containing directory has
+// no glob semantics of its own either, so pattern is taken as a literal
+// spec rather than falling back to doublestar.FilepathGlob, which would
+// look for a matching path on the real filesystem and silently find
+// nothing.
+func (identityResolver) Glob(pattern string) ([]string, error) {
+	return []string{pattern}, nil
+}
+
+// isLiteralSpec reports whether spec already names a complete source under
+// some other scheme or pseudo-scheme ("http://", "https://", "git://", a
+// "$(shell ...)"/"$(json ...)" pseudo-spec, or "-" for stdin) rather than a
+// bare path that should be resolved relative to a containing directory.
+func isLiteralSpec(spec string) bool {
+	if spec == "-" || strings.HasPrefix(spec, "$(") {
+		return true
+	}
+	return strings.Contains(spec, "://")
+}
+
+// SourceID canonically identifies the source a spec resolved to, e.g. an
+// absolute path for a local file, a normalized URL for HTTP, a
+// {repo,ref,path} tuple for a git:// include, or a content hash for
+// "$(shell ...)". Two specs that refer to the same SourceID refer to the
+// same content, even when the spec strings differ (a relative vs.
+// absolute path, a symlink, differing URL query order, ...), which is
+// what cycle detection keys on instead of comparing specs literally. An
+// empty SourceID opts a spec out of cycle detection.
+type SourceID string
+
+// Loader opens a spec for reading. It returns the Resolver that should be
+// used to resolve any spec found inside the opened content, along with
+// the SourceID of what was opened.
+type Loader interface {
+	Open(ctx context.Context, spec string) (io.ReadCloser, Resolver, SourceID, error)
+}
+
+// LoaderFunc adapts a function to a Loader.
+type LoaderFunc func(ctx context.Context, spec string) (io.ReadCloser, Resolver, SourceID, error)
+
+func (f LoaderFunc) Open(ctx context.Context, spec string) (io.ReadCloser, Resolver, SourceID, error) {
+	return f(ctx, spec)
+}
+
+// Registry maps spec schemes to the Loader responsible for them. A scheme
+// is matched by checking registered prefixes against the spec, most
+// recently registered first, so callers can Register a loader to take
+// precedence over, replace, or extend the defaults. The empty prefix is
+// the fallback used when nothing more specific matches, and is what the
+// default FileLoader is registered under.
+type Registry struct {
+	schemes  []registeredLoader
+	fallback Loader
+}
+
+type registeredLoader struct {
+	prefix string
+	loader Loader
+}
+
+// NewRegistry returns a Registry preloaded with the default loaders:
+// "-" (stdin), "$(shell " (shell command output), "$(json " (JSON-to-YAML
+// conversion), "http://", "https://", "git://" (remote Git repositories),
+// and local files for everything else.
+func NewRegistry() *Registry {
+	r := &Registry{}
+	r.Register("-", stdinLoader{})
+	r.Register("$(shell ", shellLoader{})
+	r.Register("$(json ", &jsonLoader{})
+	r.Register("http://", &httpLoader{})
+	r.Register("https://", &httpLoader{})
+	r.Register("git://", &GitLoader{})
+	r.Register("", &FileLoader{})
+	return r
+}
+
+// Register adds loader for specs matching prefix, taking precedence over
+// any previously registered loader with the same prefix. An empty prefix
+// registers the fallback loader used when no other prefix matches.
+func (r *Registry) Register(prefix string, loader Loader) {
+	if prefix == "" {
+		r.fallback = loader
+		return
+	}
+	r.schemes = append([]registeredLoader{{prefix: prefix, loader: loader}}, r.schemes...)
+}
+
+// Lookup returns the Loader registered for spec's scheme.
+func (r *Registry) Lookup(spec string) (Loader, error) {
+	for _, s := range r.schemes {
+		if strings.HasPrefix(spec, s.prefix) {
+			return s.loader, nil
+		}
+	}
+	if r.fallback != nil {
+		return r.fallback, nil
+	}
+	return nil, fmt.Errorf("yinc: no loader registered for spec %q", spec)
+}
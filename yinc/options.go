@@ -0,0 +1,70 @@
+package yinc
+
+import "github.com/go-git/go-git/v5/plumbing/transport"
+
+// Options configures a Processor. The zero value is ready to use and
+// matches the historical yinc CLI defaults.
+type Options struct {
+	// IndentWidth is the number of spaces added to the indent of each
+	// nested include level when the include line carries a leading text
+	// (e.g. a mapping key or a "- " sequence marker).
+	IndentWidth int
+
+	// IncludeTag is the YAML tag recognised as an include directive.
+	// Defaults to "!include".
+	IncludeTag string
+
+	// ReplaceTag is the YAML tag recognised as a replace directive.
+	// Defaults to "!replace".
+	ReplaceTag string
+
+	// OutputMultiDocuments marks that successive top-level specs are to be
+	// output as separate documents. Process does not read it and writes no
+	// "---" document marker itself; the caller writes one between specs,
+	// as the yinc command does.
+	OutputMultiDocuments bool
+
+	// FileSystem, if set, is used as the root for the default local-file
+	// loader instead of the real OS filesystem. This lets callers sandbox
+	// !include resolution (e.g. to an embed.FS or an in-memory tree) or
+	// disable access to the real filesystem entirely.
+	FileSystem FileSystem
+
+	// GitAuth, if set, authenticates "git://" includes against private
+	// repositories (e.g. an SSH key or a token via go-git's transport/ssh
+	// or transport/http auth methods).
+	GitAuth transport.AuthMethod
+
+	// IncludeIfTag is the YAML tag recognised as a conditional include
+	// directive. Defaults to "!include-if".
+	IncludeIfTag string
+
+	// Tags are the identifiers available to !include-if expressions, in
+	// addition to the automatic "os:<GOOS>", "arch:<GOARCH>" and
+	// "env:<NAME>" (true iff the environment variable is set non-empty).
+	// Entries here take precedence over the automatic os:/arch: tags.
+	Tags map[string]bool
+
+	// MaxIncludeDepth, if positive, caps how many levels of nested
+	// !include/!replace a Process call will follow before failing with
+	// ErrMaxIncludeDepth. This guards against runaway recursion that
+	// cycle detection alone can't catch, such as a glob that keeps
+	// matching new, non-cyclic files at every level. Zero means no limit.
+	MaxIncludeDepth int
+}
+
+func (o Options) withDefaults() Options {
+	if o.IndentWidth <= 0 {
+		o.IndentWidth = 2
+	}
+	if o.IncludeTag == "" {
+		o.IncludeTag = "!include"
+	}
+	if o.ReplaceTag == "" {
+		o.ReplaceTag = "!replace"
+	}
+	if o.IncludeIfTag == "" {
+		o.IncludeIfTag = "!include-if"
+	}
+	return o
+}
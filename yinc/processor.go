@@ -0,0 +1,54 @@
+package yinc
+
+import (
+	"context"
+	"io"
+)
+
+// Processor expands !include/!replace directives found in a YAML document
+// according to Options. A zero-value Processor uses default options; use
+// NewProcessor when non-default Options or a custom loader Registry are
+// needed.
+type Processor struct {
+	opts     Options
+	registry *Registry
+	tags     map[string]bool
+}
+
+// NewProcessor returns a Processor configured with opts. If opts.FileSystem
+// is set, it becomes the root for the default local-file loader; callers
+// needing additional schemes (or wanting to replace a default one) should
+// follow up with Register.
+func NewProcessor(opts Options) *Processor {
+	p := &Processor{
+		opts:     opts.withDefaults(),
+		registry: NewRegistry(),
+		tags:     baseTags(opts.Tags),
+	}
+	if opts.FileSystem != nil {
+		p.registry.Register("", &FileLoader{FS: opts.FileSystem})
+		p.registry.Register("$(json ", &jsonLoader{FS: opts.FileSystem})
+	}
+	if opts.GitAuth != nil {
+		p.registry.Register("git://", &GitLoader{Auth: opts.GitAuth})
+	}
+	return p
+}
+
+// Register adds or replaces the Loader responsible for specs matching
+// prefix. See Registry.Register.
+func (p *Processor) Register(prefix string, loader Loader) {
+	p.registry.Register(prefix, loader)
+}
+
+// Process reads spec (a file path, "-" for stdin, an http(s) URL, or a
+// "$(shell ...)"/"$(json ...)" pseudo-spec), expands any nested includes,
+// and writes the result to w. It returns a *ProcessError wrapping the
+// underlying cause on failure.
+func (p *Processor) Process(ctx context.Context, spec string, w io.Writer) error {
+	if p.registry == nil {
+		p = NewProcessor(p.opts)
+	}
+	stream := newStream(p.opts, p.registry, p.tags, spec, w)
+	return stream.Process(ctx)
+}
@@ -0,0 +1,47 @@
+package yinc
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// shellLoader runs a "$(shell <cmd>)" spec through the platform shell and
+// treats its stdout as the document to process.
+type shellLoader struct{}
+
+func (shellLoader) Open(ctx context.Context, spec string) (io.ReadCloser, Resolver, SourceID, error) {
+	cmdline := strings.TrimPrefix(spec, "$(shell ")
+	cmdline = strings.TrimSuffix(cmdline, ")")
+	output, err := getCmdOutput(ctx, cmdline)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	sum := sha256.Sum256(output)
+	id := SourceID("shell:" + hex.EncodeToString(sum[:]))
+	return io.NopCloser(bytes.NewReader(output)), identityResolver{}, id, nil
+}
+
+// getCmdOutput runs cmd through the platform shell and returns its
+// standard output.
+func getCmdOutput(ctx context.Context, cmd string) (output []byte, err error) {
+	var shell string
+	var flag string
+	if runtime.GOOS == "windows" {
+		shell = "cmd"
+		flag = "/c"
+	} else {
+		shell = "sh"
+		flag = "-c"
+	}
+	output, err = exec.CommandContext(ctx, shell, flag, cmd).Output()
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
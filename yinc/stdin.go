@@ -0,0 +1,16 @@
+package yinc
+
+import (
+	"context"
+	"io"
+	"os"
+)
+
+// stdinLoader reads from the process's standard input. It is registered
+// for the "-" spec (and the empty spec, handled by the processor before a
+// Loader is even consulted).
+type stdinLoader struct{}
+
+func (stdinLoader) Open(ctx context.Context, spec string) (io.ReadCloser, Resolver, SourceID, error) {
+	return io.NopCloser(os.Stdin), identityResolver{}, "stdin", nil
+}
@@ -0,0 +1,198 @@
+package yinc
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// Stream processes a single include spec, writing its (possibly expanded)
+// contents to Writer. Nested !include/!replace directives spawn child
+// Streams that share the same Writer and a link back to their parent for
+// cycle detection.
+type Stream struct {
+	Spec        string
+	Indent      []byte
+	FirstIndent []byte
+	Writer      io.Writer
+
+	opts     Options
+	registry *Registry
+	tags     map[string]bool
+	resolver Resolver // resolves specs found inside this stream's content
+	id       SourceID // this stream's canonical source, set once opened
+	depth    int
+	parent   *Stream
+	out      int64
+}
+
+// newStream creates a Stream for spec, inheriting opts, registry and tags.
+func newStream(opts Options, registry *Registry, tags map[string]bool, spec string, writer io.Writer) *Stream {
+	return &Stream{
+		Spec:     spec,
+		Writer:   writer,
+		opts:     opts,
+		registry: registry,
+		tags:     tags,
+	}
+}
+
+func (s *Stream) WriteIndent(data ...[]byte) (n int, err error) {
+	if s.out == 0 && s.FirstIndent != nil {
+		n, err = s.Write(s.FirstIndent)
+	} else {
+		n, err = s.Write(s.Indent)
+	}
+	if err != nil {
+		return 0, err
+	}
+	for _, d := range data {
+		np, err := s.Write(d)
+		if err != nil {
+			return n, err
+		}
+		n += np
+	}
+	return n, nil
+}
+
+func (s *Stream) Write(data []byte) (n int, err error) {
+	n, err = s.Writer.Write(data)
+	s.out += int64(n)
+	return n, err
+}
+
+// Process reads the stream's spec line by line, expanding include/replace
+// directives into child Streams, until EOF or an error occurs.
+func (s *Stream) Process(ctx context.Context) error {
+	if s.Spec == "" {
+		s.Spec = "-"
+	}
+	loader, err := s.registry.Lookup(s.Spec)
+	if err != nil {
+		return wrapErr(s.Spec, 0, err)
+	}
+	reader, resolver, id, err := loader.Open(ctx, s.Spec)
+	if err != nil {
+		return wrapErr(s.Spec, 0, err)
+	}
+	defer reader.Close()
+	s.resolver = resolver
+	s.id = id
+	if id != "" {
+		for p := s.parent; p != nil; p = p.parent {
+			if p.id == id {
+				return wrapErr(s.Spec, 0, fmt.Errorf("%s: %w", s.Spec, ErrCyclicInclude))
+			}
+		}
+	}
+
+	bufReader := bufio.NewReaderSize(reader, 4096)
+	lineElements := NewLine(s.opts.IncludeTag, s.opts.ReplaceTag, s.opts.IncludeIfTag)
+	lineNo := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return wrapErr(s.Spec, lineNo, err)
+		}
+		line, _, err := bufReader.ReadLine()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return wrapErr(s.Spec, lineNo, err)
+		}
+		lineNo++
+		if lineElements.Match(line) {
+			tag := lineElements.submatch.tag
+			spec := lineElements.submatch.spec
+			if tag == s.opts.IncludeIfTag {
+				expr, rest, err := splitCondition(spec)
+				if err != nil {
+					return wrapErr(s.Spec, lineNo, err)
+				}
+				cond, err := parseTagExpr(expr)
+				if err != nil {
+					return wrapErr(s.Spec, lineNo, err)
+				}
+				if !cond.eval(s.tags) {
+					continue
+				}
+				tag = s.opts.IncludeTag
+				spec = rest
+			}
+			newIndent := string(s.Indent) + lineElements.submatch.indent
+			files, err := s.glob(spec)
+			if err != nil {
+				return wrapErr(s.Spec, lineNo, err)
+			}
+			for _, file := range files {
+				var firstIndent string
+				indent := newIndent
+				if lineElements.submatch.text != "" && tag == s.opts.IncludeTag {
+					s.WriteIndent([]byte(lineElements.submatch.indent + lineElements.submatch.text))
+					if lineElements.submatch.text != "-" {
+						s.Writer.Write([]byte("\n"))
+					}
+					indent += strings.Repeat(" ", s.opts.IndentWidth)
+					if lineElements.submatch.text == "-" {
+						firstIndent = " "
+					}
+				}
+				sub, err := s.SubStream(file, indent, firstIndent)
+				if err != nil {
+					return wrapErr(s.Spec, lineNo, err)
+				}
+				if err := sub.Process(ctx); err != nil {
+					return err
+				}
+			}
+		} else {
+			if _, err := s.WriteIndent(line, []byte("\n")); err != nil {
+				return wrapErr(s.Spec, lineNo, err)
+			}
+		}
+	}
+	return nil
+}
+
+// glob expands spec, resolved against this stream's Resolver, into the
+// list of matching specs understood by the registry.
+func (s *Stream) glob(spec string) ([]string, error) {
+	if g, ok := s.resolver.(globResolver); ok {
+		return g.Glob(spec)
+	}
+	return doublestar.FilepathGlob(s.resolver.Resolve(spec))
+}
+
+// ErrCyclicInclude is returned when a spec's canonical SourceID matches
+// one already being processed by an ancestor Stream.
+var ErrCyclicInclude = errors.New("cyclic include detected")
+
+// ErrMaxIncludeDepth is returned by SubStream once Options.MaxIncludeDepth
+// nesting levels have been exceeded.
+var ErrMaxIncludeDepth = errors.New("max include depth exceeded")
+
+// SubStream creates a child Stream for a nested include/replace spec.
+// Cyclic includes are only detectable once the child is opened and its
+// SourceID is known (see Process); SubStream itself only enforces
+// Options.MaxIncludeDepth, which catches runaway non-cyclic recursion
+// (e.g. an exploding glob) without needing to open anything.
+func (s *Stream) SubStream(spec string, indent string, firstIndent string) (*Stream, error) {
+	depth := s.depth + 1
+	if s.opts.MaxIncludeDepth > 0 && depth > s.opts.MaxIncludeDepth {
+		return nil, fmt.Errorf("%s: %w (%d)", spec, ErrMaxIncludeDepth, s.opts.MaxIncludeDepth)
+	}
+	sub := newStream(s.opts, s.registry, s.tags, spec, s.Writer)
+	sub.Indent = []byte(indent)
+	if firstIndent != "" {
+		sub.FirstIndent = []byte(firstIndent)
+	}
+	sub.parent = s
+	sub.depth = depth
+	return sub, nil
+}
@@ -0,0 +1,121 @@
+package yinc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// memLoader serves specs out of an in-memory map, assigning each spec a
+// SourceID through id (or the spec itself if id is nil). It requires no
+// filesystem or network access, so it can exercise Stream's cycle and
+// depth handling in isolation.
+type memLoader struct {
+	files map[string]string
+	id    func(spec string) SourceID
+}
+
+func (m *memLoader) Open(ctx context.Context, spec string) (io.ReadCloser, Resolver, SourceID, error) {
+	content, ok := m.files[spec]
+	if !ok {
+		return nil, nil, "", fmt.Errorf("no such spec: %s", spec)
+	}
+	id := SourceID(spec)
+	if m.id != nil {
+		id = m.id(spec)
+	}
+	return io.NopCloser(strings.NewReader(content)), memResolver{}, id, nil
+}
+
+// memResolver resolves every spec to itself and takes every glob pattern
+// as a literal spec, so Stream.glob never falls back to matching "mem:..."
+// specs as disk paths via doublestar.FilepathGlob.
+type memResolver struct{}
+
+func (memResolver) Resolve(spec string) string { return spec }
+
+func (memResolver) Glob(pattern string) ([]string, error) { return []string{pattern}, nil }
+
+func newMemRegistry(loader *memLoader) *Registry {
+	r := &Registry{}
+	r.Register("mem:", loader)
+	return r
+}
+
+func TestStreamProcessDetectsCycleByCanonicalID(t *testing.T) {
+	loader := &memLoader{
+		files: map[string]string{
+			"mem:a":       "!include mem:b\n",
+			"mem:b":       "!include mem:a-alias\n",
+			"mem:a-alias": "unreachable\n",
+		},
+		// mem:a and mem:a-alias are different specs but the same source.
+		id: func(spec string) SourceID {
+			if spec == "mem:a" || spec == "mem:a-alias" {
+				return "canon:a"
+			}
+			return SourceID(spec)
+		},
+	}
+	var out strings.Builder
+	stream := newStream(Options{}.withDefaults(), newMemRegistry(loader), nil, "mem:a", &out)
+	err := stream.Process(context.Background())
+	if !errors.Is(err, ErrCyclicInclude) {
+		t.Fatalf("Process() error = %v, want ErrCyclicInclude", err)
+	}
+}
+
+func TestStreamProcessAllowsRepeatedNonCyclicSource(t *testing.T) {
+	// "mem:a" is included twice from siblings, but never from one of its
+	// own ancestors, so this isn't a cycle.
+	loader := &memLoader{
+		files: map[string]string{
+			"mem:root": "!include mem:a\n!include mem:a\n",
+			"mem:a":    "leaf\n",
+		},
+	}
+	var out strings.Builder
+	stream := newStream(Options{}.withDefaults(), newMemRegistry(loader), nil, "mem:root", &out)
+	if err := stream.Process(context.Background()); err != nil {
+		t.Fatalf("Process() error = %v, want nil", err)
+	}
+	if want := "leaf\nleaf\n"; out.String() != want {
+		t.Fatalf("output = %q, want %q", out.String(), want)
+	}
+}
+
+func TestStreamSubStreamEnforcesMaxIncludeDepth(t *testing.T) {
+	loader := &memLoader{
+		files: map[string]string{
+			"mem:d0": "!include mem:d1\n",
+			"mem:d1": "!include mem:d2\n",
+			"mem:d2": "!include mem:d3\n",
+			"mem:d3": "leaf\n",
+		},
+	}
+	opts := Options{MaxIncludeDepth: 2}.withDefaults()
+	var out strings.Builder
+	stream := newStream(opts, newMemRegistry(loader), nil, "mem:d0", &out)
+	err := stream.Process(context.Background())
+	if !errors.Is(err, ErrMaxIncludeDepth) {
+		t.Fatalf("Process() error = %v, want ErrMaxIncludeDepth", err)
+	}
+}
+
+func TestStreamSubStreamWithinMaxIncludeDepthSucceeds(t *testing.T) {
+	loader := &memLoader{
+		files: map[string]string{
+			"mem:d0": "!include mem:d1\n",
+			"mem:d1": "leaf\n",
+		},
+	}
+	opts := Options{MaxIncludeDepth: 2}.withDefaults()
+	var out strings.Builder
+	stream := newStream(opts, newMemRegistry(loader), nil, "mem:d0", &out)
+	if err := stream.Process(context.Background()); err != nil {
+		t.Fatalf("Process() error = %v, want nil", err)
+	}
+}
@@ -0,0 +1,156 @@
+package yinc
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// tagExpr is a boolean expression over identifiers, built from the same
+// grammar go/build uses for file-level build constraints: identifiers
+// combined with &&, ||, !, and parentheses.
+type tagExpr interface {
+	eval(tags map[string]bool) bool
+}
+
+type tagIdent string
+
+func (e tagIdent) eval(tags map[string]bool) bool {
+	id := string(e)
+	if v, ok := tags[id]; ok {
+		return v
+	}
+	if name, ok := strings.CutPrefix(id, "env:"); ok {
+		return os.Getenv(name) != ""
+	}
+	return false
+}
+
+type tagNot struct{ x tagExpr }
+
+func (e tagNot) eval(tags map[string]bool) bool { return !e.x.eval(tags) }
+
+type tagAnd struct{ x, y tagExpr }
+
+func (e tagAnd) eval(tags map[string]bool) bool { return e.x.eval(tags) && e.y.eval(tags) }
+
+type tagOr struct{ x, y tagExpr }
+
+func (e tagOr) eval(tags map[string]bool) bool { return e.x.eval(tags) || e.y.eval(tags) }
+
+// parseTagExpr parses a boolean expression like "prod && !debug" or
+// "(linux || darwin) && !ci" into a tagExpr ready for evaluation.
+func parseTagExpr(s string) (tagExpr, error) {
+	p := &tagExprParser{s: s}
+	p.next()
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok != "" {
+		return nil, fmt.Errorf("unexpected %q after expression", p.s[p.pos-len(p.tok):])
+	}
+	return expr, nil
+}
+
+type tagExprParser struct {
+	s   string
+	pos int
+	tok string
+}
+
+func (p *tagExprParser) skipSpace() {
+	for p.pos < len(p.s) && p.s[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+// next advances to the next token: "&&", "||", "!", "(", ")", or an
+// identifier: a run of characters other than space, "!", "(" and ")"
+// that ends before any "&&" or "||".
+func (p *tagExprParser) next() {
+	p.skipSpace()
+	if p.pos >= len(p.s) {
+		p.tok = ""
+		return
+	}
+	switch {
+	case strings.HasPrefix(p.s[p.pos:], "&&"):
+		p.tok = "&&"
+		p.pos += 2
+	case strings.HasPrefix(p.s[p.pos:], "||"):
+		p.tok = "||"
+		p.pos += 2
+	case p.s[p.pos] == '!', p.s[p.pos] == '(', p.s[p.pos] == ')':
+		p.tok = p.s[p.pos : p.pos+1]
+		p.pos++
+	default:
+		start := p.pos
+		for p.pos < len(p.s) && !strings.ContainsRune(" !()", rune(p.s[p.pos])) &&
+			!strings.HasPrefix(p.s[p.pos:], "&&") && !strings.HasPrefix(p.s[p.pos:], "||") {
+			p.pos++
+		}
+		p.tok = p.s[start:p.pos]
+	}
+}
+
+func (p *tagExprParser) parseOr() (tagExpr, error) {
+	x, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok == "||" {
+		p.next()
+		y, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		x = tagOr{x, y}
+	}
+	return x, nil
+}
+
+func (p *tagExprParser) parseAnd() (tagExpr, error) {
+	x, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok == "&&" {
+		p.next()
+		y, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		x = tagAnd{x, y}
+	}
+	return x, nil
+}
+
+func (p *tagExprParser) parseUnary() (tagExpr, error) {
+	switch p.tok {
+	case "!":
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return tagNot{x}, nil
+	case "(":
+		p.next()
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok != ")" {
+			return nil, fmt.Errorf("missing closing paren")
+		}
+		p.next()
+		return x, nil
+	case "", "&&", "||", ")":
+		return nil, fmt.Errorf("expected identifier, found %q", p.tok)
+	default:
+		id := p.tok
+		p.next()
+		return tagIdent(id), nil
+	}
+}
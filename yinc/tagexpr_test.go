@@ -0,0 +1,64 @@
+package yinc
+
+import "testing"
+
+func TestParseTagExpr(t *testing.T) {
+	tags := map[string]bool{"prod": true, "debug": false, "linux": true, "darwin": false}
+
+	cases := []struct {
+		expr string
+		want bool
+	}{
+		{"prod", true},
+		{"debug", false},
+		{"!debug", true},
+		{"prod && !debug", true},
+		{"debug && prod", false},
+		{"debug || prod", true},
+		{"(linux || darwin) && !ci", true},
+		{"linux && darwin", false},
+		{"!(prod && debug)", true},
+		{"missing", false},
+	}
+	for _, c := range cases {
+		expr, err := parseTagExpr(c.expr)
+		if err != nil {
+			t.Fatalf("parseTagExpr(%q): %v", c.expr, err)
+		}
+		if got := expr.eval(tags); got != c.want {
+			t.Errorf("parseTagExpr(%q).eval(...) = %v, want %v", c.expr, got, c.want)
+		}
+	}
+}
+
+func TestParseTagExprEnv(t *testing.T) {
+	t.Setenv("YINC_TEST_TAG", "1")
+	expr, err := parseTagExpr("env:YINC_TEST_TAG")
+	if err != nil {
+		t.Fatalf("parseTagExpr: %v", err)
+	}
+	if !expr.eval(nil) {
+		t.Error("expected env:YINC_TEST_TAG to evaluate true when set")
+	}
+
+	expr, err = parseTagExpr("env:YINC_TEST_TAG_UNSET")
+	if err != nil {
+		t.Fatalf("parseTagExpr: %v", err)
+	}
+	if expr.eval(nil) {
+		t.Error("expected unset env: tag to evaluate false")
+	}
+}
+
+func TestParseTagExprErrors(t *testing.T) {
+	cases := []string{
+		"",
+		"&&",
+		"(prod",
+	}
+	for _, expr := range cases {
+		if _, err := parseTagExpr(expr); err == nil {
+			t.Errorf("parseTagExpr(%q): expected error, got nil", expr)
+		}
+	}
+}